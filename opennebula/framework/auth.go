@@ -0,0 +1,108 @@
+package opennebula
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	providerCfg "github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// resolvedCredentials is the username/secret pair used to build the goca
+// client, together with the scheme that produced it.
+type resolvedCredentials struct {
+	username string
+	secret   string
+	scheme   providerCfg.AuthScheme
+}
+
+// resolveCredentials determines the username/secret pair used to
+// authenticate against the OpenNebula XML-RPC API, applying this
+// precedence: explicit configuration attributes (username/password or
+// auth_token), then an external credential-helper command, then a
+// ONE_AUTH style file, then environment variables.
+func resolveCredentials(m opennebulaProviderModel) (resolvedCredentials, error) {
+	if !m.Username.IsNull() && !m.Password.IsNull() {
+		return resolvedCredentials{m.Username.ValueString(), m.Password.ValueString(), providerCfg.AuthSchemePassword}, nil
+	}
+
+	if !m.AuthToken.IsNull() {
+		username, secret, err := splitUserAuth(m.AuthToken.ValueString())
+		if err != nil {
+			return resolvedCredentials{}, fmt.Errorf("auth_token: %w", err)
+		}
+		return resolvedCredentials{username, secret, providerCfg.AuthSchemeToken}, nil
+	}
+
+	if !m.CredentialCommand.IsNull() {
+		out, err := runCredentialHelper(m.CredentialCommand.ValueString())
+		if err != nil {
+			return resolvedCredentials{}, fmt.Errorf("credential_command: %w", err)
+		}
+		username, secret, err := splitUserAuth(out)
+		if err != nil {
+			return resolvedCredentials{}, fmt.Errorf("credential_command: %w", err)
+		}
+		return resolvedCredentials{username, secret, providerCfg.AuthSchemeHelper}, nil
+	}
+
+	authFile := ""
+	switch {
+	case !m.TokenFile.IsNull():
+		authFile = m.TokenFile.ValueString()
+	case os.Getenv("OPENNEBULA_AUTH_FILE") != "":
+		authFile = os.Getenv("OPENNEBULA_AUTH_FILE")
+	case os.Getenv("ONE_AUTH") != "":
+		authFile = os.Getenv("ONE_AUTH")
+	}
+	if authFile != "" {
+		content, err := os.ReadFile(authFile)
+		if err != nil {
+			return resolvedCredentials{}, fmt.Errorf("token_file: %w", err)
+		}
+		username, secret, err := splitUserAuth(string(content))
+		if err != nil {
+			return resolvedCredentials{}, fmt.Errorf("token_file: %w", err)
+		}
+		return resolvedCredentials{username, secret, providerCfg.AuthSchemeOneAuthFile}, nil
+	}
+
+	username := os.Getenv("OPENNEBULA_USERNAME")
+	password := os.Getenv("OPENNEBULA_PASSWORD")
+	if !m.Username.IsNull() {
+		username = m.Username.ValueString()
+	}
+	if !m.Password.IsNull() {
+		password = m.Password.ValueString()
+	}
+	return resolvedCredentials{username, password, providerCfg.AuthSchemePassword}, nil
+}
+
+// splitUserAuth splits a `username:secret` credential string as produced by
+// a ONE_AUTH file, `oneuser token-create`, or a credential helper.
+func splitUserAuth(raw string) (string, string, error) {
+	line := strings.TrimSpace(raw)
+	if nl := strings.IndexAny(line, "\r\n"); nl >= 0 {
+		line = line[:nl]
+	}
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected a \"username:secret\" credential, got %q", raw)
+	}
+	return parts[0], parts[1], nil
+}
+
+// runCredentialHelper executes the configured credential command and
+// returns its trimmed stdout.
+func runCredentialHelper(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run credential helper: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
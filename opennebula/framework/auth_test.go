@@ -0,0 +1,40 @@
+package opennebula
+
+import "testing"
+
+func TestSplitUserAuth(t *testing.T) {
+	cases := []struct {
+		name       string
+		raw        string
+		wantUser   string
+		wantSecret string
+		wantErr    bool
+	}{
+		{name: "simple", raw: "oneadmin:secret", wantUser: "oneadmin", wantSecret: "secret"},
+		{name: "trailing newline", raw: "oneadmin:secret\n", wantUser: "oneadmin", wantSecret: "secret"},
+		{name: "secret contains colon", raw: "oneadmin:sec:ret", wantUser: "oneadmin", wantSecret: "sec:ret"},
+		{name: "surrounding whitespace", raw: "  oneadmin:secret  \n", wantUser: "oneadmin", wantSecret: "secret"},
+		{name: "missing colon", raw: "oneadmin", wantErr: true},
+		{name: "empty username", raw: ":secret", wantErr: true},
+		{name: "empty secret", raw: "oneadmin:", wantErr: true},
+		{name: "empty string", raw: "", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, secret, err := splitUserAuth(tc.raw)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("splitUserAuth(%q): expected error, got none", tc.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitUserAuth(%q): unexpected error: %v", tc.raw, err)
+			}
+			if user != tc.wantUser || secret != tc.wantSecret {
+				t.Errorf("splitUserAuth(%q) = (%q, %q), want (%q, %q)", tc.raw, user, secret, tc.wantUser, tc.wantSecret)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package config holds the state produced by the provider's Configure call
+// and shared with every resource and data source through
+// resp.DataSourceData / resp.ResourceData.
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	ver "github.com/hashicorp/go-version"
+
+	"github.com/OpenNebula/one/src/oca/go/src/goca"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/utils"
+)
+
+// AuthScheme identifies which credential mechanism the provider resolved
+// for the current session.
+type AuthScheme string
+
+const (
+	// AuthSchemePassword is the historical username/password mode.
+	AuthSchemePassword AuthScheme = "password"
+	// AuthSchemeToken is the `username:token` style described in the
+	// OpenNebula authentication documentation.
+	AuthSchemeToken AuthScheme = "token"
+	// AuthSchemeHelper means credentials were produced by an external
+	// credential-helper command.
+	AuthSchemeHelper AuthScheme = "helper"
+	// AuthSchemeOneAuthFile means credentials were read from a ONE_AUTH
+	// style file.
+	AuthSchemeOneAuthFile AuthScheme = "one_auth_file"
+)
+
+// Profile describes a single named connection to an OpenNebula
+// cluster/zone, analogous to a kubeconfig context.
+type Profile struct {
+	Endpoint     string
+	FlowEndpoint string
+	Username     string
+	Password     string
+	Insecure     bool
+	DefaultTags  map[string]string
+}
+
+// Provider is the shared state handed to every resource and data source.
+type Provider struct {
+	// Controller is the goca.Controller for CurrentContext. It is kept
+	// alongside Controllers for resources that have not been updated to
+	// take a `context` argument yet.
+	Controller  *goca.Controller
+	OneVersion  *ver.Version
+	Mutex       utils.MutexKV
+	DefaultTags map[string]string
+	// IgnoreTags lists tag keys/prefixes excluded from every resource's
+	// computed `tags_all`, regardless of where they were set.
+	IgnoreTags utils.IgnoreTags
+
+	// AuthScheme records which credential mechanism produced the
+	// provider's top-level credentials, so resources can log it without
+	// re-deriving it. Profiles from `profile` blocks or an
+	// OPENNEBULA_CONFIG file use their own username/password instead.
+	AuthScheme AuthScheme
+
+	// Profiles holds every named connection profile known to the
+	// provider, keyed by name. A "default" entry always exists, built
+	// from the provider's top-level attributes.
+	Profiles map[string]Profile
+	// CurrentContext is the profile used by resources that do not set
+	// their own `context` argument.
+	CurrentContext string
+	// Controllers holds one goca.Controller per entry in Profiles.
+	Controllers map[string]*goca.Controller
+}
+
+// ControllerFor returns the goca.Controller for the named context/profile.
+// An empty name resolves to CurrentContext. Resources should call this
+// instead of reading Controller directly so that an optional `context`
+// argument on the resource schema can select a different OpenNebula
+// cluster/zone.
+func (p *Provider) ControllerFor(name string) (*goca.Controller, error) {
+	if name == "" {
+		name = p.CurrentContext
+	}
+	controller, ok := p.Controllers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown OpenNebula context %q", name)
+	}
+	return controller, nil
+}
+
+// TagsAllFor computes the tags_all attribute for a resource's own tags,
+// applying default_tags and ignore_tags uniformly. default_tags are read
+// from the named profile's own DefaultTags, not the provider's top-level
+// ones, so a resource pinned to a `context` other than CurrentContext
+// picks up that profile's defaults instead. An empty contextName resolves
+// to CurrentContext, mirroring ControllerFor.
+func (p *Provider) TagsAllFor(contextName string, tags map[string]string) map[string]string {
+	if contextName == "" {
+		contextName = p.CurrentContext
+	}
+	return utils.TagsAll(tags, p.Profiles[contextName].DefaultTags, p.IgnoreTags)
+}
+
+// ApplyTagsAll reads the Go map backing tags, computes tags_all via
+// TagsAllFor, and writes the result into tagsAll. Every resource in the
+// framework calls this after a successful Create/Read/Update so tags_all
+// stays uniform without duplicating the merge logic.
+func (p *Provider) ApplyTagsAll(ctx context.Context, contextName string, tags *types.Map, tagsAll *types.Map) error {
+	tagMap := map[string]string{}
+	if !tags.IsNull() && !tags.IsUnknown() {
+		if diags := tags.ElementsAs(ctx, &tagMap, false); diags.HasError() {
+			return fmt.Errorf("failed to read tags: %v", diags)
+		}
+	}
+
+	merged := p.TagsAllFor(contextName, tagMap)
+
+	mergedValue, diags := types.MapValueFrom(ctx, types.StringType, merged)
+	if diags.HasError() {
+		return fmt.Errorf("failed to encode tags_all: %v", diags)
+	}
+	*tagsAll = mergedValue
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/utils"
+)
+
+func TestTagsAllFor(t *testing.T) {
+	cfg := &Provider{
+		CurrentContext: "default",
+		Profiles: map[string]Profile{
+			"default": {DefaultTags: map[string]string{"env": "prod", "owner": "team-a"}},
+		},
+		IgnoreTags: utils.IgnoreTags{Keys: []string{"owner"}},
+	}
+
+	got := cfg.TagsAllFor("", map[string]string{"Name": "svc", "env": "dev"})
+	want := map[string]string{"env": "dev", "Name": "svc"}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("TagsAllFor[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+	if _, ok := got["owner"]; ok {
+		t.Errorf("TagsAllFor: expected owner to be ignored, got %v", got)
+	}
+}
+
+func TestTagsAllForUsesNamedProfileDefaults(t *testing.T) {
+	cfg := &Provider{
+		CurrentContext: "default",
+		Profiles: map[string]Profile{
+			"default": {DefaultTags: map[string]string{"env": "prod"}},
+			"other":   {DefaultTags: map[string]string{"env": "staging"}},
+		},
+	}
+
+	got := cfg.TagsAllFor("other", map[string]string{})
+	if got["env"] != "staging" {
+		t.Errorf("TagsAllFor[env] = %q, want %q (from the \"other\" profile, not CurrentContext)", got["env"], "staging")
+	}
+}
+
+func TestApplyTagsAllWithNullTags(t *testing.T) {
+	cfg := &Provider{
+		CurrentContext: "default",
+		Profiles: map[string]Profile{
+			"default": {DefaultTags: map[string]string{"env": "prod"}},
+		},
+	}
+
+	tags := types.MapNull(types.StringType)
+	var tagsAll types.Map
+	if err := cfg.ApplyTagsAll(context.Background(), "", &tags, &tagsAll); err != nil {
+		t.Fatalf("ApplyTagsAll: unexpected error: %v", err)
+	}
+
+	var got map[string]string
+	if diags := tagsAll.ElementsAs(context.Background(), &got, false); diags.HasError() {
+		t.Fatalf("failed to read tagsAll: %v", diags)
+	}
+	if got["env"] != "prod" {
+		t.Errorf("tagsAll[env] = %q, want %q", got["env"], "prod")
+	}
+}
@@ -0,0 +1,191 @@
+package opennebula
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/OpenNebula/one/src/oca/go/src/goca"
+
+	providerCfg "github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/transport"
+)
+
+// fileProfile is the on-disk shape of a single profile entry in an
+// OPENNEBULA_CONFIG file.
+type fileProfile struct {
+	Endpoint     string            `json:"endpoint" yaml:"endpoint"`
+	FlowEndpoint string            `json:"flow_endpoint" yaml:"flow_endpoint"`
+	Username     string            `json:"username" yaml:"username"`
+	Password     string            `json:"password" yaml:"password"`
+	Insecure     bool              `json:"insecure" yaml:"insecure"`
+	DefaultTags  map[string]string `json:"default_tags" yaml:"default_tags"`
+}
+
+// fileConfig is the on-disk shape of an OPENNEBULA_CONFIG file: a set of
+// named profiles plus which one is active by default, mirroring how the
+// Kubernetes Terraform provider loads a kubeconfig.
+type fileConfig struct {
+	CurrentContext string                 `json:"current_context" yaml:"current_context"`
+	Profiles       map[string]fileProfile `json:"profiles" yaml:"profiles"`
+}
+
+// loadProfilesFile reads the profile set referenced by the
+// OPENNEBULA_CONFIG environment variable, if any. It returns a nil map
+// and an empty context when the variable is unset.
+func loadProfilesFile() (map[string]providerCfg.Profile, string, error) {
+	path := os.Getenv("OPENNEBULA_CONFIG")
+	if path == "" {
+		return nil, "", nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read OPENNEBULA_CONFIG file: %w", err)
+	}
+
+	var fc fileConfig
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(raw, &fc)
+	} else {
+		err = yaml.Unmarshal(raw, &fc)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse OPENNEBULA_CONFIG file %q: %w", path, err)
+	}
+
+	profiles := make(map[string]providerCfg.Profile, len(fc.Profiles))
+	for name, p := range fc.Profiles {
+		profiles[name] = providerCfg.Profile{
+			Endpoint:     p.Endpoint,
+			FlowEndpoint: p.FlowEndpoint,
+			Username:     p.Username,
+			Password:     p.Password,
+			Insecure:     p.Insecure,
+			DefaultTags:  p.DefaultTags,
+		}
+	}
+	return profiles, fc.CurrentContext, nil
+}
+
+// resolveProfiles builds the full set of named connection profiles
+// (contexts) known to the provider: an implicit "default" profile built
+// from the provider's top-level attributes, overridden by any profiles
+// loaded from the OPENNEBULA_CONFIG file, in turn overridden by any
+// `profile` blocks declared in the provider configuration.
+func resolveProfiles(ctx context.Context, m opennebulaProviderModel, defaultProfile providerCfg.Profile) (map[string]providerCfg.Profile, string, error) {
+	profiles := map[string]providerCfg.Profile{
+		"default": defaultProfile,
+	}
+
+	fileProfiles, fileContext, err := loadProfilesFile()
+	if err != nil {
+		return nil, "", err
+	}
+	for name, p := range fileProfiles {
+		profiles[name] = p
+	}
+
+	if !m.Profiles.IsNull() {
+		var blocks []profileModel
+		diags := m.Profiles.ElementsAs(ctx, &blocks, false)
+		if diags.HasError() {
+			return nil, "", fmt.Errorf("failed to read profile blocks: %v", diags.Errors())
+		}
+		for _, b := range blocks {
+			prof := providerCfg.Profile{
+				Endpoint:     b.Endpoint.ValueString(),
+				FlowEndpoint: b.FlowEndpoint.ValueString(),
+				Username:     b.Username.ValueString(),
+				Password:     b.Password.ValueString(),
+				Insecure:     b.Insecure.ValueBool(),
+			}
+			if !b.DefaultTags.IsNull() {
+				tags := make(map[string]string, len(b.DefaultTags.Elements()))
+				if diags := b.DefaultTags.ElementsAs(ctx, &tags, false); diags.HasError() {
+					return nil, "", fmt.Errorf("failed to read profile %q default_tags: %v", b.Name.ValueString(), diags.Errors())
+				}
+				prof.DefaultTags = tags
+			}
+			profiles[b.Name.ValueString()] = prof
+		}
+	}
+
+	currentContext := "default"
+	if fileContext != "" {
+		currentContext = fileContext
+	}
+	if v := os.Getenv("OPENNEBULA_CONTEXT"); v != "" {
+		currentContext = v
+	}
+	if !m.CurrentContext.IsNull() {
+		currentContext = m.CurrentContext.ValueString()
+	}
+
+	if _, ok := profiles[currentContext]; !ok {
+		return nil, "", fmt.Errorf("current_context %q does not match any known profile", currentContext)
+	}
+
+	return profiles, currentContext, nil
+}
+
+// validateProfile requires that a profile carry a complete XML-RPC
+// connection (endpoint, username, password) before it is dialed. This is
+// checked per profile, once every profile/file/block override has been
+// merged, rather than on the provider's raw top-level attributes - a
+// profile that is declared but never referenced by a resource's `context`
+// is still validated, since resolveProfiles has no way to know in advance
+// which ones will be used.
+func validateProfile(name string, prof providerCfg.Profile) error {
+	var missing []string
+	if prof.Endpoint == "" {
+		missing = append(missing, "endpoint")
+	}
+	if prof.Username == "" {
+		missing = append(missing, "username")
+	}
+	if prof.Password == "" {
+		missing = append(missing, "password")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("profile %q is missing: %s", name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// profileConnectionEqual reports whether two profiles describe the same
+// OpenNebula connection, ignoring DefaultTags (a map, and so not directly
+// comparable with ==). It is used to detect whether the "default" profile
+// was left unmodified by an OPENNEBULA_CONFIG file or `profile` block, so
+// its controller can be reused instead of dialed twice.
+func profileConnectionEqual(a, b providerCfg.Profile) bool {
+	return a.Endpoint == b.Endpoint &&
+		a.FlowEndpoint == b.FlowEndpoint &&
+		a.Username == b.Username &&
+		a.Password == b.Password &&
+		a.Insecure == b.Insecure
+}
+
+// buildController dials the goca.Controller (optionally flow-enabled) for
+// a single profile, applying the provider-wide retry/backoff and
+// rate-limit settings.
+func buildController(prof providerCfg.Profile, retryCfg transport.RetryConfig, rateCfg transport.RateLimitConfig) (*goca.Controller, error) {
+	tr := transport.NewRetryingTransport(&http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: prof.Insecure},
+	}, retryCfg, rateCfg)
+
+	client := goca.NewClient(goca.NewConfig(prof.Username, prof.Password, prof.Endpoint), &http.Client{Transport: tr})
+
+	if prof.FlowEndpoint == "" {
+		return goca.NewController(client), nil
+	}
+
+	flowClient := goca.NewDefaultFlowClient(goca.NewFlowConfig(prof.Username, prof.Password, prof.FlowEndpoint))
+	return goca.NewGenericController(client, flowClient), nil
+}
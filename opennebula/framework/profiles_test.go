@@ -0,0 +1,295 @@
+package opennebula
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	providerCfg "github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/transport"
+)
+
+// profileObjectType mirrors profileModel's tfsdk tags, so tests can build
+// the `profile` list attribute the same way Terraform core would.
+var profileObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"name":          types.StringType,
+		"endpoint":      types.StringType,
+		"flow_endpoint": types.StringType,
+		"username":      types.StringType,
+		"password":      types.StringType,
+		"insecure":      types.BoolType,
+		"default_tags":  types.MapType{ElemType: types.StringType},
+	},
+}
+
+func profilesListValue(t *testing.T, blocks []profileModel) types.List {
+	t.Helper()
+	l, diags := types.ListValueFrom(context.Background(), profileObjectType, blocks)
+	if diags.HasError() {
+		t.Fatalf("failed to build profile list: %v", diags.Errors())
+	}
+	return l
+}
+
+func TestResolveProfilesDefaultOnly(t *testing.T) {
+	m := opennebulaProviderModel{
+		Profiles:       types.ListNull(profileObjectType),
+		CurrentContext: types.StringNull(),
+	}
+	defaultProfile := providerCfg.Profile{Endpoint: "https://default.example.com"}
+
+	profiles, currentContext, err := resolveProfiles(context.Background(), m, defaultProfile)
+	if err != nil {
+		t.Fatalf("resolveProfiles: unexpected error: %v", err)
+	}
+	if currentContext != "default" {
+		t.Errorf("currentContext = %q, want %q", currentContext, "default")
+	}
+	if profiles["default"].Endpoint != defaultProfile.Endpoint {
+		t.Errorf("profiles[default].Endpoint = %q, want %q", profiles["default"].Endpoint, defaultProfile.Endpoint)
+	}
+}
+
+func TestResolveProfilesFileOverridesDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "onecfg.json")
+	writeConfigFile(t, path, fileConfig{
+		CurrentContext: "from-file",
+		Profiles: map[string]fileProfile{
+			"default":   {Endpoint: "https://from-file.example.com"},
+			"from-file": {Endpoint: "https://other.example.com"},
+		},
+	})
+	t.Setenv("OPENNEBULA_CONFIG", path)
+
+	m := opennebulaProviderModel{
+		Profiles:       types.ListNull(profileObjectType),
+		CurrentContext: types.StringNull(),
+	}
+	defaultProfile := providerCfg.Profile{Endpoint: "https://top-level.example.com"}
+
+	profiles, currentContext, err := resolveProfiles(context.Background(), m, defaultProfile)
+	if err != nil {
+		t.Fatalf("resolveProfiles: unexpected error: %v", err)
+	}
+	if currentContext != "from-file" {
+		t.Errorf("currentContext = %q, want %q", currentContext, "from-file")
+	}
+	if profiles["default"].Endpoint != "https://from-file.example.com" {
+		t.Errorf("profiles[default].Endpoint = %q, want the file's override", profiles["default"].Endpoint)
+	}
+}
+
+func TestResolveProfilesBlockOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "onecfg.json")
+	writeConfigFile(t, path, fileConfig{
+		Profiles: map[string]fileProfile{
+			"default": {Endpoint: "https://from-file.example.com"},
+		},
+	})
+	t.Setenv("OPENNEBULA_CONFIG", path)
+
+	m := opennebulaProviderModel{
+		Profiles: profilesListValue(t, []profileModel{
+			{Name: types.StringValue("default"), Endpoint: types.StringValue("https://from-block.example.com")},
+		}),
+		CurrentContext: types.StringNull(),
+	}
+	defaultProfile := providerCfg.Profile{Endpoint: "https://top-level.example.com"}
+
+	profiles, _, err := resolveProfiles(context.Background(), m, defaultProfile)
+	if err != nil {
+		t.Fatalf("resolveProfiles: unexpected error: %v", err)
+	}
+	if profiles["default"].Endpoint != "https://from-block.example.com" {
+		t.Errorf("profiles[default].Endpoint = %q, want the `profile` block's override", profiles["default"].Endpoint)
+	}
+}
+
+func TestResolveProfilesEnvOverridesFileContext(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "onecfg.json")
+	writeConfigFile(t, path, fileConfig{
+		CurrentContext: "from-file",
+		Profiles: map[string]fileProfile{
+			"default":  {Endpoint: "https://default.example.com"},
+			"from-env": {Endpoint: "https://from-env.example.com"},
+		},
+	})
+	t.Setenv("OPENNEBULA_CONFIG", path)
+	t.Setenv("OPENNEBULA_CONTEXT", "from-env")
+
+	m := opennebulaProviderModel{
+		Profiles:       types.ListNull(profileObjectType),
+		CurrentContext: types.StringNull(),
+	}
+
+	_, currentContext, err := resolveProfiles(context.Background(), m, providerCfg.Profile{})
+	if err != nil {
+		t.Fatalf("resolveProfiles: unexpected error: %v", err)
+	}
+	if currentContext != "from-env" {
+		t.Errorf("currentContext = %q, want %q", currentContext, "from-env")
+	}
+}
+
+func TestResolveProfilesConfigAttributeOverridesEnv(t *testing.T) {
+	t.Setenv("OPENNEBULA_CONTEXT", "from-env")
+
+	m := opennebulaProviderModel{
+		Profiles: profilesListValue(t, []profileModel{
+			{Name: types.StringValue("from-env")},
+			{Name: types.StringValue("from-config")},
+		}),
+		CurrentContext: types.StringValue("from-config"),
+	}
+
+	_, currentContext, err := resolveProfiles(context.Background(), m, providerCfg.Profile{})
+	if err != nil {
+		t.Fatalf("resolveProfiles: unexpected error: %v", err)
+	}
+	if currentContext != "from-config" {
+		t.Errorf("currentContext = %q, want %q (the current_context attribute should win over OPENNEBULA_CONTEXT)", currentContext, "from-config")
+	}
+}
+
+func TestResolveProfilesUnknownCurrentContext(t *testing.T) {
+	m := opennebulaProviderModel{
+		Profiles:       types.ListNull(profileObjectType),
+		CurrentContext: types.StringValue("does-not-exist"),
+	}
+
+	if _, _, err := resolveProfiles(context.Background(), m, providerCfg.Profile{}); err == nil {
+		t.Fatal("resolveProfiles: expected an error for an unknown current_context")
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	cases := []struct {
+		name    string
+		prof    providerCfg.Profile
+		wantErr bool
+	}{
+		{name: "complete", prof: providerCfg.Profile{Endpoint: "e", Username: "u", Password: "p"}},
+		{name: "missing endpoint", prof: providerCfg.Profile{Username: "u", Password: "p"}, wantErr: true},
+		{name: "missing username", prof: providerCfg.Profile{Endpoint: "e", Password: "p"}, wantErr: true},
+		{name: "missing password", prof: providerCfg.Profile{Endpoint: "e", Username: "u"}, wantErr: true},
+		{name: "missing everything", prof: providerCfg.Profile{}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProfile("some-profile", tc.prof)
+			if tc.wantErr && err == nil {
+				t.Fatal("validateProfile: expected an error, got none")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("validateProfile: unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestProfileConnectionEqual(t *testing.T) {
+	base := providerCfg.Profile{Endpoint: "e", Username: "u", Password: "p", Insecure: true}
+
+	cases := []struct {
+		name  string
+		other providerCfg.Profile
+		want  bool
+	}{
+		{name: "identical", other: base, want: true},
+		{
+			name: "differs only by default_tags",
+			other: providerCfg.Profile{
+				Endpoint: "e", Username: "u", Password: "p", Insecure: true,
+				DefaultTags: map[string]string{"env": "prod"},
+			},
+			want: true,
+		},
+		{name: "different endpoint", other: providerCfg.Profile{Endpoint: "other", Username: "u", Password: "p", Insecure: true}, want: false},
+		{name: "different insecure", other: providerCfg.Profile{Endpoint: "e", Username: "u", Password: "p", Insecure: false}, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := profileConnectionEqual(base, tc.other); got != tc.want {
+				t.Errorf("profileConnectionEqual = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadProfilesFileUnset(t *testing.T) {
+	t.Setenv("OPENNEBULA_CONFIG", "")
+
+	profiles, currentContext, err := loadProfilesFile()
+	if err != nil {
+		t.Fatalf("loadProfilesFile: unexpected error: %v", err)
+	}
+	if profiles != nil || currentContext != "" {
+		t.Errorf("loadProfilesFile() = (%v, %q), want (nil, \"\") when OPENNEBULA_CONFIG is unset", profiles, currentContext)
+	}
+}
+
+func TestLoadProfilesFileYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "onecfg.yaml")
+	contents := "current_context: prod\nprofiles:\n  prod:\n    endpoint: https://prod.example.com\n    username: oneadmin\n    password: secret\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+	t.Setenv("OPENNEBULA_CONFIG", path)
+
+	profiles, currentContext, err := loadProfilesFile()
+	if err != nil {
+		t.Fatalf("loadProfilesFile: unexpected error: %v", err)
+	}
+	if currentContext != "prod" {
+		t.Errorf("currentContext = %q, want %q", currentContext, "prod")
+	}
+	if profiles["prod"].Endpoint != "https://prod.example.com" {
+		t.Errorf("profiles[prod].Endpoint = %q, want %q", profiles["prod"].Endpoint, "https://prod.example.com")
+	}
+}
+
+func TestBuildController(t *testing.T) {
+	cases := []struct {
+		name string
+		prof providerCfg.Profile
+	}{
+		{name: "xml-rpc only", prof: providerCfg.Profile{Endpoint: "https://one.example.com", Username: "u", Password: "p"}},
+		{name: "with flow endpoint", prof: providerCfg.Profile{Endpoint: "https://one.example.com", FlowEndpoint: "https://one.example.com:2474", Username: "u", Password: "p"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			controller, err := buildController(tc.prof, transport.RetryConfig{}, transport.RateLimitConfig{})
+			if err != nil {
+				t.Fatalf("buildController: unexpected error: %v", err)
+			}
+			if controller == nil {
+				t.Fatal("buildController: expected a non-nil controller")
+			}
+		})
+	}
+}
+
+// writeConfigFile marshals cfg as JSON and writes it to path.
+func writeConfigFile(t *testing.T, path string, cfg fileConfig) {
+	t.Helper()
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal test config file: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package opennebula
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	"github.com/OpenNebula/one/src/oca/go/src/goca"
 	ver "github.com/hashicorp/go-version"
@@ -21,6 +23,8 @@ import (
 	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
 	providerCfg "github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
 	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/resources"
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/resources/flow"
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/transport"
 	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/utils"
 )
 
@@ -38,12 +42,61 @@ func (p *OpenNebulaProvider) Metadata(_ context.Context, _ provider.MetadataRequ
 }
 
 type opennebulaProviderModel struct {
+	Endpoint          types.String `tfsdk:"endpoint"`
+	FlowEndpoint      types.String `tfsdk:"flow_endpoint"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	AuthToken         types.String `tfsdk:"auth_token"`
+	TokenFile         types.String `tfsdk:"token_file"`
+	CredentialCommand types.String `tfsdk:"credential_command"`
+	ClientCert        types.String `tfsdk:"client_cert"`
+	ClientKey         types.String `tfsdk:"client_key"`
+	Insecure          types.Bool   `tfsdk:"insecure"`
+	DefaultTags       types.List   `tfsdk:"default_tags"`
+	IgnoreTags        types.Object `tfsdk:"ignore_tags"`
+	CurrentContext    types.String `tfsdk:"current_context"`
+	Profiles          types.List   `tfsdk:"profile"`
+	Retry             types.Object `tfsdk:"retry"`
+	RateLimit         types.Object `tfsdk:"rate_limit"`
+}
+
+// defaultTagsBlockModel is a single `default_tags` block. Repeated blocks
+// are merged in declaration order, so later blocks override earlier ones
+// on key conflicts.
+type defaultTagsBlockModel struct {
+	Tags map[string]string `tfsdk:"tags"`
+}
+
+// ignoreTagsModel is the `ignore_tags` block.
+type ignoreTagsModel struct {
+	KeyPrefixes types.List `tfsdk:"key_prefixes"`
+	Keys        types.List `tfsdk:"keys"`
+}
+
+// retryModel is the `retry` block.
+type retryModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+	RetryOn     types.List   `tfsdk:"retry_on"`
+}
+
+// rateLimitModel is the `rate_limit` block.
+type rateLimitModel struct {
+	RPS   types.Float64 `tfsdk:"rps"`
+	Burst types.Int64   `tfsdk:"burst"`
+}
+
+// profileModel is a single `profile` block: a named OpenNebula connection,
+// analogous to a kubeconfig context.
+type profileModel struct {
+	Name         types.String `tfsdk:"name"`
 	Endpoint     types.String `tfsdk:"endpoint"`
 	FlowEndpoint types.String `tfsdk:"flow_endpoint"`
 	Username     types.String `tfsdk:"username"`
 	Password     types.String `tfsdk:"password"`
 	Insecure     types.Bool   `tfsdk:"insecure"`
-	DefaultTags  types.Set    `tfsdk:"default_tags"`
+	DefaultTags  types.Map    `tfsdk:"default_tags"`
 }
 
 func (p *OpenNebulaProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -66,18 +119,51 @@ func (p *OpenNebulaProvider) Schema(ctx context.Context, req provider.SchemaRequ
 			},
 			"password": schema.StringAttribute{
 				Optional:    true,
+				Sensitive:   true,
 				Description: "The password for the user",
 				//MarkdownDescription: "",
 			},
+			"auth_token": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A `username:token` credential, as produced by `oneuser token-create`, used instead of username/password",
+				//MarkdownDescription: "",
+			},
+			"token_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a ONE_AUTH style file holding a `username:token` or `username:password` credential. Defaults to the OPENNEBULA_AUTH_FILE or ONE_AUTH environment variable",
+				//MarkdownDescription: "",
+			},
+			"credential_command": schema.StringAttribute{
+				Optional:    true,
+				Description: "External command whose stdout produces a `username:secret` credential, for integrating with secret managers such as the 1Password CLI",
+				//MarkdownDescription: "",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM encoded client certificate used for mutual TLS against the OpenNebula XML-RPC endpoint",
+				//MarkdownDescription: "",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client private key used for mutual TLS against the OpenNebula XML-RPC endpoint",
+				//MarkdownDescription: "",
+			},
 			"insecure": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Disable TLS validation",
 				//MarkdownDescription: "",
 			},
+			"current_context": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of the profile used by resources that do not set their own `context` argument. Defaults to \"default\", or the OPENNEBULA_CONTEXT environment variable",
+				//MarkdownDescription: "",
+			},
 		},
 		Blocks: map[string]schema.Block{
-			"default_tags": schema.SetNestedBlock{
-				Description: "Add default tags to the resources",
+			"default_tags": schema.ListNestedBlock{
+				Description: "Default tags applied to every resource's tags_all. Repeated blocks are merged in declaration order, with later blocks taking precedence on key conflicts",
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"tags": schema.MapAttribute{
@@ -88,6 +174,93 @@ func (p *OpenNebulaProvider) Schema(ctx context.Context, req provider.SchemaRequ
 					},
 				},
 			},
+			"ignore_tags": schema.SingleNestedBlock{
+				Description: "Tag keys excluded from every resource's computed tags_all, so tags added by external tooling don't cause drift",
+				Attributes: map[string]schema.Attribute{
+					"key_prefixes": schema.ListAttribute{
+						Optional:    true,
+						Description: "Tag key prefixes to ignore",
+						ElementType: types.StringType,
+					},
+					"keys": schema.ListAttribute{
+						Optional:    true,
+						Description: "Exact tag keys to ignore",
+						ElementType: types.StringType,
+					},
+				},
+			},
+			"profile": schema.ListNestedBlock{
+				Description: "An additional named OpenNebula connection profile (context), for managing multiple clusters/zones from one workspace. The endpoint/username/password/insecure attributes above are always available as the \"default\" profile",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "The name used to select this profile, via current_context or a resource's context argument",
+						},
+						"endpoint": schema.StringAttribute{
+							Optional:    true,
+							Description: "The URL to this profile's OpenNebula XML-RPC endpoint",
+						},
+						"flow_endpoint": schema.StringAttribute{
+							Optional:    true,
+							Description: "The URL to this profile's OpenNebula Flow server",
+						},
+						"username": schema.StringAttribute{
+							Optional:    true,
+							Description: "The ID of the user to identify as for this profile",
+						},
+						"password": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password for the user for this profile",
+						},
+						"insecure": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Disable TLS validation for this profile",
+						},
+						"default_tags": schema.MapAttribute{
+							Optional:    true,
+							Description: "Default tags applied to resources using this profile",
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"retry": schema.SingleNestedBlock{
+				Description: "Retry/backoff behavior for requests to the OpenNebula XML-RPC and Flow endpoints",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Maximum number of attempts per request, including the first. Defaults to 1 (no retries)",
+					},
+					"min_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "Minimum backoff between retries, as a Go duration string (e.g. \"500ms\"). Defaults to \"500ms\"",
+					},
+					"max_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "Maximum backoff between retries, as a Go duration string. Defaults to \"30s\"",
+					},
+					"retry_on": schema.ListAttribute{
+						Optional:    true,
+						Description: "Failure classes to retry: \"timeout\", \"5xx\", \"rpc_busy\". Defaults to all three whenever max_attempts > 1",
+						ElementType: types.StringType,
+					},
+				},
+			},
+			"rate_limit": schema.SingleNestedBlock{
+				Description: "Client-side throttling of outbound requests to the OpenNebula endpoints",
+				Attributes: map[string]schema.Attribute{
+					"rps": schema.Float64Attribute{
+						Optional:    true,
+						Description: "Maximum sustained requests per second. Unset disables throttling",
+					},
+					"burst": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Maximum burst size above rps. Defaults to 1",
+					},
+				},
+			},
 		},
 	}
 }
@@ -137,8 +310,6 @@ func (p *OpenNebulaProvider) Configure(ctx context.Context, req provider.Configu
 
 	endpoint := os.Getenv("OPENNEBULA_ENDPOINT")
 	flowEndpoint := os.Getenv("OPENNEBULA_FLOW_ENDPOINT")
-	username := os.Getenv("OPENNEBULA_USERNAME")
-	password := os.Getenv("OPENNEBULA_PASSWORD")
 
 	insecureStr := os.Getenv("OPENNEBULA_INSECURE")
 	insecure := false
@@ -159,124 +330,227 @@ func (p *OpenNebulaProvider) Configure(ctx context.Context, req provider.Configu
 		endpoint = config.Endpoint.ValueString()
 	}
 
-	if !config.Endpoint.IsNull() {
-		flowEndpoint = config.Endpoint.ValueString()
-	}
-
-	if !config.Username.IsNull() {
-		username = config.Username.ValueString()
-	}
-
-	if !config.Password.IsNull() {
-		password = config.Password.ValueString()
+	if !config.FlowEndpoint.IsNull() {
+		flowEndpoint = config.FlowEndpoint.ValueString()
 	}
 
 	if !config.Insecure.IsNull() {
 		insecure = config.Insecure.ValueBool()
 	}
 
-	// If any of the expected configurations are missing, return
-	// errors with provider-specific guidance.
-
-	if endpoint == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("endpoint"),
-			"Missing OpenNebula XML-RPC endpoint",
-			"The provider cannot create the OpenNebula XML-RPC client as there is a missing or empty value for the OpenNebula API endpoint. "+
-				"Set the endpoint value in the configuration or use the OPENNEBULA_ENDPOINT environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
-	}
-
-	if username == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("username"),
-			"Missing OpenNebula account username",
-			"The provider cannot create the OpenNebula XML-RPC client as there is a missing or empty value for the OpenNebula username. "+
-				"Set the endpoint value in the configuration or use the OPENNEBULA_USERNAME environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
-	}
-
-	if password == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("endpoint"),
-			"Missing OpenNebula account password",
-			"The provider cannot create the OpenNebula XML-RPC client as there is a missing or empty value for the OpenNebula password. "+
-				"Set the endpoint value in the configuration or use the OPENNEBULA_PASSWORD environment variable. "+
-				"If either is already set, ensure the value is not empty.",
+	// Resolve credentials following the documented precedence: explicit
+	// config attributes, an external credential helper, a ONE_AUTH style
+	// file, then environment variables.
+	creds, err := resolveCredentials(config)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to resolve OpenNebula credentials",
+			err.Error(),
 		)
-	}
-
-	if resp.Diagnostics.HasError() {
 		return
 	}
+	username := creds.username
+	password := creds.secret
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
-	}
+	// Unlike endpoint/username/password, we deliberately do NOT hard-fail
+	// here if any of these are empty: a user managing every cluster
+	// through `profile` blocks or an OPENNEBULA_CONFIG file, with no
+	// top-level connection at all, is a valid configuration. Each
+	// profile's completeness is validated individually, once the
+	// top-level attributes have been merged with the file/block
+	// overrides below.
 
-	// Create a new OpenNebula client using the configuration values
-	client := goca.NewClient(goca.NewConfig(username,
-		password,
-		endpoint),
-		&http.Client{Transport: tr})
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
 
-	versionStr, err := goca.NewController(client).SystemVersion()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to get OpenNebula release number",
-			err.Error(),
-		)
-		return
+	if !config.ClientCert.IsNull() || !config.ClientKey.IsNull() {
+		cert, err := tls.X509KeyPair([]byte(config.ClientCert.ValueString()), []byte(config.ClientKey.ValueString()))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to load OpenNebula client certificate",
+				"client_cert and client_key must both be set to a valid PEM encoded certificate/key pair: "+err.Error(),
+			)
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
-	version, err := ver.NewVersion(versionStr)
+
+	retryCfg, rateCfg, err := resolveRetryConfig(ctx, config)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Failed to parse OpenNebula version",
+			"Failed to read retry/rate_limit configuration",
 			err.Error(),
 		)
 		return
 	}
 
-	log.Printf("[INFO] OpenNebula version: %s", versionStr)
+	tr := transport.NewRetryingTransport(&http.Transport{
+		TLSClientConfig: tlsConfig,
+	}, retryCfg, rateCfg)
+
+	// Client built from the top-level attributes (including any mTLS
+	// certificate). It is only ever dialed if the merged "default"
+	// profile below turns out unchanged from these attributes.
+	client := goca.NewClient(goca.NewConfig(username,
+		password,
+		endpoint),
+		&http.Client{Transport: tr})
 
 	cfg := &OpenNebulaProvider{
 		providerCfg.Provider{
-			OneVersion: version,
 			Mutex:      *utils.NewMutexKV(),
+			AuthScheme: creds.scheme,
 		},
 	}
 
-	if len(flowEndpoint) > 0 {
-		flowClient := goca.NewDefaultFlowClient(
-			goca.NewFlowConfig(username,
-				password,
-				flowEndpoint))
+	log.Printf("[INFO] OpenNebula auth scheme: %s", creds.scheme)
+
+	// Terraform has already resolved any ${var.*}/${local.*} interpolation
+	// in the tag values by the time we see them here, so default_tags
+	// blocks are merged as plain maps, in declaration order.
+	var tagBlocks []defaultTagsBlockModel
+	if !config.DefaultTags.IsNull() {
+		if diags := config.DefaultTags.ElementsAs(ctx, &tagBlocks, false); diags.HasError() {
+			resp.Diagnostics.AddError(
+				"Failed to read default_tags",
+				fmt.Sprintf("%v", diags.Errors()),
+			)
+			return
+		}
+	}
 
-		cfg.Controller = goca.NewGenericController(client, flowClient)
-	} else {
-		cfg.Controller = goca.NewController(client)
+	tagMaps := make([]map[string]string, 0, len(tagBlocks))
+	for _, b := range tagBlocks {
+		tagMaps = append(tagMaps, b.Tags)
 	}
+	cfg.DefaultTags = utils.MergeTags(tagMaps...)
+	log.Printf("[DEBUG] default_tags: %+v", cfg.DefaultTags)
+
+	if !config.IgnoreTags.IsNull() {
+		var it ignoreTagsModel
+		if diags := config.IgnoreTags.As(ctx, &it, basetypes.ObjectAsOptions{}); diags.HasError() {
+			resp.Diagnostics.AddError(
+				"Failed to read ignore_tags",
+				fmt.Sprintf("%v", diags.Errors()),
+			)
+			return
+		}
 
-	var tags DefaultTags
-	for _, t := range config.DefaultTags.Elements() {
-		element, err := t.ToTerraformValue(ctx)
-		if err != nil {
-			log.Print("[DEBUG] ToTerraformValue err: ", err)
-			continue
+		var ignoreTags utils.IgnoreTags
+		if !it.KeyPrefixes.IsNull() {
+			if diags := it.KeyPrefixes.ElementsAs(ctx, &ignoreTags.KeyPrefixes, false); diags.HasError() {
+				resp.Diagnostics.AddError(
+					"Failed to read ignore_tags.key_prefixes",
+					fmt.Sprintf("%v", diags.Errors()),
+				)
+				return
+			}
 		}
-		err = element.As(&tags)
-		if err != nil {
-			log.Print("[DEBUG] As err: ", err)
-			continue
+		if !it.Keys.IsNull() {
+			if diags := it.Keys.ElementsAs(ctx, &ignoreTags.Keys, false); diags.HasError() {
+				resp.Diagnostics.AddError(
+					"Failed to read ignore_tags.keys",
+					fmt.Sprintf("%v", diags.Errors()),
+				)
+				return
+			}
 		}
+		cfg.IgnoreTags = ignoreTags
+	}
+
+	// Build the "default" profile from the top-level attributes, then
+	// merge in any profiles declared via `profile` blocks or the
+	// OPENNEBULA_CONFIG file, kubeconfig-context style. This intentionally
+	// happens before any endpoint/username/password is required to be
+	// non-empty: a workspace that defines every cluster through
+	// OPENNEBULA_CONFIG, with no top-level connection at all, is valid.
+	defaultProfile := providerCfg.Profile{
+		Endpoint:     endpoint,
+		FlowEndpoint: flowEndpoint,
+		Username:     username,
+		Password:     password,
+		Insecure:     insecure,
+		DefaultTags:  cfg.DefaultTags,
+	}
+
+	profiles, currentContext, err := resolveProfiles(ctx, config, defaultProfile)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to resolve OpenNebula profiles",
+			err.Error(),
+		)
+		return
 	}
 
-	if len(tags.Elements) > 0 {
-		cfg.DefaultTags = tags.Elements
+	// Only now, with every profile merged, do we require a complete
+	// endpoint/username/password per profile actually declared - and only
+	// dial the one resources will use by default (currentContext).
+	controllers := make(map[string]*goca.Controller, len(profiles))
+	var version *ver.Version
+	for name, prof := range profiles {
+		// The implicit "default" profile is left empty when no top-level
+		// connection is configured; it is only required to be complete
+		// when it is the current context.
+		emptyDefault := name == "default" && name != currentContext &&
+			profileConnectionEqual(prof, defaultProfile) && prof.Endpoint == ""
+		if err := validateProfile(name, prof); err != nil && !emptyDefault {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Incomplete OpenNebula profile %q", name),
+				err.Error(),
+			)
+			return
+		}
+
+		var controller *goca.Controller
+		if name == "default" && profileConnectionEqual(prof, defaultProfile) {
+			// Unmodified by a `profile` block or OPENNEBULA_CONFIG:
+			// reuse the client already built from the top-level
+			// attributes (and its mTLS certificate) instead of
+			// dialing a second, identical one.
+			if prof.FlowEndpoint == "" {
+				controller = goca.NewController(client)
+			} else {
+				flowClient := goca.NewDefaultFlowClient(
+					goca.NewFlowConfig(prof.Username, prof.Password, prof.FlowEndpoint))
+				controller = goca.NewGenericController(client, flowClient)
+			}
+		} else {
+			controller, err = buildController(prof, retryCfg, rateCfg)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					fmt.Sprintf("Failed to build OpenNebula client for profile %q", name),
+					err.Error(),
+				)
+				return
+			}
+		}
+		controllers[name] = controller
+
+		if name == currentContext {
+			versionStr, err := controller.SystemVersion()
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Failed to get OpenNebula release number",
+					err.Error(),
+				)
+				return
+			}
+			version, err = ver.NewVersion(versionStr)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Failed to parse OpenNebula version",
+					err.Error(),
+				)
+				return
+			}
+			log.Printf("[INFO] OpenNebula version: %s", versionStr)
+		}
 	}
-	log.Printf("[DEBUG] default_tags: %+v", tags.Elements)
+
+	cfg.OneVersion = version
+	cfg.Profiles = profiles
+	cfg.CurrentContext = currentContext
+	cfg.Controllers = controllers
+	cfg.Controller = controllers[currentContext]
 
 	// Make the OpenNebula controller available during DataSource and Resource
 	// type Configure methods.
@@ -290,6 +564,15 @@ func (p *OpenNebulaProvider) Resources(ctx context.Context) []func() resource.Re
 		func() resource.Resource {
 			return resources.NewCluster()
 		},
+		func() resource.Resource {
+			return flow.NewServiceTemplate()
+		},
+		func() resource.Resource {
+			return flow.NewService()
+		},
+		func() resource.Resource {
+			return flow.NewServiceRoleScale()
+		},
 	}
 }
 
@@ -298,5 +581,11 @@ func (p *OpenNebulaProvider) DataSources(ctx context.Context) []func() datasourc
 		func() datasource.DataSource {
 			return NewExampleDataSource()
 		},
+		func() datasource.DataSource {
+			return flow.NewServiceTemplateDataSource()
+		},
+		func() datasource.DataSource {
+			return flow.NewServiceDataSource()
+		},
 	}
-}
\ No newline at end of file
+}
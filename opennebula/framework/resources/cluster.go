@@ -0,0 +1,209 @@
+// Package resources holds the framework migration's non-Flow resources
+// (currently just opennebula_cluster).
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// clusterModel is the `opennebula_cluster` resource model.
+type clusterModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Tags    types.Map    `tfsdk:"tags"`
+	TagsAll types.Map    `tfsdk:"tags_all"`
+	Context types.String `tfsdk:"context"`
+}
+
+type clusterResource struct {
+	cfg *config.Provider
+}
+
+// NewCluster returns the `opennebula_cluster` resource.
+func NewCluster() resource.Resource {
+	return &clusterResource{}
+}
+
+func (r *clusterResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster"
+}
+
+func (r *clusterResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an OpenNebula cluster",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the cluster",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the cluster",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				Description: "Tags tracked in Terraform state for this cluster",
+				ElementType: types.StringType,
+			},
+			"tags_all": schema.MapAttribute{
+				Computed:    true,
+				Description: "tags merged with the provider's default_tags, minus any ignore_tags matches. Diff suppression should compare against this instead of tags",
+				ElementType: types.StringType,
+			},
+			"context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this resource. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (r *clusterResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *clusterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan clusterModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := r.cfg.ControllerFor(plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := controller.Clusters().Create(plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create OpenNebula cluster", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(id))
+	if err := r.cfg.ApplyTagsAll(ctx, plan.Context.ValueString(), &plan.Tags, &plan.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *clusterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state clusterModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := r.cfg.ControllerFor(state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid cluster ID", err.Error())
+		return
+	}
+
+	info, err := controller.Cluster(id).Info()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read OpenNebula cluster", err.Error())
+		return
+	}
+	state.Name = types.StringValue(info.Name)
+	if err := r.cfg.ApplyTagsAll(ctx, state.Context.ValueString(), &state.Tags, &state.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *clusterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan clusterModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := r.cfg.ControllerFor(plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid cluster ID", err.Error())
+		return
+	}
+
+	if err := controller.Cluster(id).Rename(plan.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to rename OpenNebula cluster", err.Error())
+		return
+	}
+
+	if err := r.cfg.ApplyTagsAll(ctx, plan.Context.ValueString(), &plan.Tags, &plan.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *clusterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state clusterModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := r.cfg.ControllerFor(state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid cluster ID", err.Error())
+		return
+	}
+
+	if err := controller.Cluster(id).Delete(); err != nil {
+		resp.Diagnostics.AddError("Failed to delete OpenNebula cluster", err.Error())
+		return
+	}
+}
+
+func (r *clusterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,23 @@
+// Package flow provides the OneFlow subsystem: resources and data sources
+// for managing multi-tier OpenNebula applications (service templates,
+// services, and role scaling) through the Flow XML-RPC API, instead of
+// only the lower-level VM/image primitives.
+package flow
+
+import (
+	"fmt"
+
+	"github.com/OpenNebula/one/src/oca/go/src/goca"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// controllerFor resolves the goca.Controller to use for a CRUD call,
+// honoring the resource's optional `context` argument.
+func controllerFor(providerData any, contextName string) (*goca.Controller, error) {
+	cfg, ok := providerData.(*config.Provider)
+	if !ok || cfg == nil {
+		return nil, fmt.Errorf("unexpected provider data type %T, expected *config.Provider", providerData)
+	}
+	return cfg.ControllerFor(contextName)
+}
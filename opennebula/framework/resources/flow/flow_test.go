@@ -0,0 +1,26 @@
+package flow
+
+import (
+	"testing"
+
+	"github.com/OpenNebula/one/src/oca/go/src/goca"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+func TestControllerForRejectsWrongProviderDataType(t *testing.T) {
+	if _, err := controllerFor("not a *config.Provider", ""); err == nil {
+		t.Fatal("controllerFor: expected an error for an unexpected provider data type")
+	}
+}
+
+func TestControllerForUnknownContext(t *testing.T) {
+	cfg := &config.Provider{
+		CurrentContext: "default",
+		Controllers:    map[string]*goca.Controller{},
+	}
+
+	if _, err := controllerFor(cfg, "missing"); err == nil {
+		t.Fatal("controllerFor: expected an error for an unknown context")
+	}
+}
@@ -0,0 +1,358 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/OpenNebula/one/src/oca/go/src/goca"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// serviceModel is the `opennebula_service` resource model.
+type serviceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	TemplateID      types.Int64  `tfsdk:"template_id"`
+	ExtraTemplate   types.String `tfsdk:"extra_template"`
+	RecoverOnDelete types.Bool   `tfsdk:"recover_on_delete"`
+	State           types.String `tfsdk:"state"`
+	Tags            types.Map    `tfsdk:"tags"`
+	TagsAll         types.Map    `tfsdk:"tags_all"`
+	Context         types.String `tfsdk:"context"`
+}
+
+type serviceResource struct {
+	cfg *config.Provider
+}
+
+// NewService returns the `opennebula_service` resource.
+func NewService() resource.Resource {
+	return &serviceResource{}
+}
+
+func (r *serviceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service"
+}
+
+func (r *serviceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Instantiates and manages an OpenNebula OneFlow service from a service template",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the service",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the service, as reported by OneFlow (the service template's name at instantiation). A configured value is not sent to OneFlow and does not rename the service",
+			},
+			"template_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the opennebula_service_template to instantiate",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"extra_template": schema.StringAttribute{
+				Optional:    true,
+				Description: "Extra JSON merged into the service template at instantiation time, e.g. role-specific overrides. OneFlow has no API to update this after instantiation, so changing it replaces the service",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"recover_on_delete": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If the service is in a FAILED state when destroyed, call OneFlow's recover operation before deleting it",
+			},
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "Current OneFlow service state",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				Description: "Tags tracked in Terraform state for this service",
+				ElementType: types.StringType,
+			},
+			"tags_all": schema.MapAttribute{
+				Computed:    true,
+				Description: "tags merged with the provider's default_tags, minus any ignore_tags matches. Diff suppression should compare against this instead of tags",
+				ElementType: types.StringType,
+			},
+			"context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this resource. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (r *serviceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *serviceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan serviceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	extra := plan.ExtraTemplate.ValueString()
+	id, err := controller.STemplate(int(plan.TemplateID.ValueInt64())).Instantiate(extra)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to instantiate OneFlow service", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(id))
+	r.readInto(ctx, controller, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state serviceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	r.readInto(ctx, controller, &state, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *serviceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan serviceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	r.readInto(ctx, controller, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state serviceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service ID", err.Error())
+		return
+	}
+
+	// Recover is idempotent on a healthy service, so when the user opts
+	// in we call it unconditionally rather than trying to special-case
+	// every FAILED_* role state.
+	if state.RecoverOnDelete.ValueBool() {
+		if err := controller.Service(id).Recover(true); err != nil {
+			resp.Diagnostics.AddError("Failed to recover OneFlow service before deleting", err.Error())
+			return
+		}
+	}
+
+	if err := controller.Service(id).Delete(); err != nil {
+		resp.Diagnostics.AddError("Failed to delete OneFlow service", err.Error())
+		return
+	}
+}
+
+func (r *serviceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// readInto refreshes m from the OneFlow API, preserving m.ID.
+func (r *serviceResource) readInto(ctx context.Context, controller *goca.Controller, m *serviceModel, diags *diag.Diagnostics) {
+	id, err := strconv.Atoi(m.ID.ValueString())
+	if err != nil {
+		diags.AddError("Invalid service ID", err.Error())
+		return
+	}
+
+	info, err := controller.Service(id).Info()
+	if err != nil {
+		diags.AddError("Failed to read OneFlow service", err.Error())
+		return
+	}
+
+	m.Name = types.StringValue(info.Name)
+	m.State = types.StringValue(fmt.Sprintf("%d", info.State))
+
+	if err := r.cfg.ApplyTagsAll(ctx, m.Context.ValueString(), &m.Tags, &m.TagsAll); err != nil {
+		diags.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+}
+
+// serviceDataSourceModel is the `opennebula_service` data source model.
+type serviceDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	State   types.String `tfsdk:"state"`
+	TagsAll types.Map    `tfsdk:"tags_all"`
+	Context types.String `tfsdk:"context"`
+}
+
+type serviceDataSource struct {
+	cfg *config.Provider
+}
+
+// NewServiceDataSource returns the `opennebula_service` data source.
+func NewServiceDataSource() datasource.DataSource {
+	return &serviceDataSource{}
+}
+
+func (d *serviceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service"
+}
+
+func (d *serviceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dschema.Schema{
+		Description: "Retrieves information about an existing OneFlow service",
+		Attributes: map[string]dschema.Attribute{
+			"id": dschema.StringAttribute{
+				Required:    true,
+				Description: "ID of the service",
+			},
+			"name": dschema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the service",
+			},
+			"state": dschema.StringAttribute{
+				Computed:    true,
+				Description: "Current OneFlow service state",
+			},
+			"tags_all": dschema.MapAttribute{
+				Computed:    true,
+				Description: "tags merged with the provider's default_tags, minus any ignore_tags matches",
+				ElementType: types.StringType,
+			},
+			"context": dschema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this data source. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (d *serviceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected data source configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.cfg = cfg
+}
+
+func (d *serviceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state serviceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(d.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service ID", err.Error())
+		return
+	}
+
+	info, err := controller.Service(id).Info()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read OneFlow service", err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(info.Name)
+	state.State = types.StringValue(fmt.Sprintf("%d", info.State))
+
+	// OneFlow does not track Terraform-managed tags for existing
+	// services, so the data source only surfaces the provider's
+	// default_tags via tags_all.
+	noTags := types.MapNull(types.StringType)
+	if err := d.cfg.ApplyTagsAll(ctx, state.Context.ValueString(), &noTags, &state.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,187 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// serviceRoleScaleModel is the `opennebula_service_role_scale` resource
+// model. Unlike most resources this one represents an action (scale a
+// role to a cardinality) rather than an object OpenNebula owns on its
+// own; destroying it only removes it from Terraform state, it does not
+// scale the role back down.
+type serviceRoleScaleModel struct {
+	ID          types.String `tfsdk:"id"`
+	ServiceID   types.Int64  `tfsdk:"service_id"`
+	RoleName    types.String `tfsdk:"role_name"`
+	Cardinality types.Int64  `tfsdk:"cardinality"`
+	Force       types.Bool   `tfsdk:"force"`
+	Cooldown    types.String `tfsdk:"cooldown"`
+	Context     types.String `tfsdk:"context"`
+}
+
+type serviceRoleScaleResource struct {
+	cfg *config.Provider
+}
+
+// NewServiceRoleScale returns the `opennebula_service_role_scale` resource.
+func NewServiceRoleScale() resource.Resource {
+	return &serviceRoleScaleResource{}
+}
+
+func (r *serviceRoleScaleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_role_scale"
+}
+
+func (r *serviceRoleScaleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Scales a role of an opennebula_service to a given cardinality. Destroying this resource only drops it from state; it does not scale the role back down",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of this resource, as \"service_id/role_name\"",
+			},
+			"service_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "ID of the opennebula_service owning the role",
+			},
+			"role_name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the role to scale",
+			},
+			"cardinality": schema.Int64Attribute{
+				Required:    true,
+				Description: "Target number of VM instances for the role",
+			},
+			"force": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Force the scale operation even if it skips the role's cooldown period",
+			},
+			"cooldown": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long to wait, as a Go duration string (e.g. \"2m\"), after requesting the scale before considering it complete",
+			},
+			"context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this resource. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (r *serviceRoleScaleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *serviceRoleScaleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan serviceRoleScaleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.scale(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Failed to scale OneFlow service role", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(fmt.Sprintf("%d/%s", plan.ServiceID.ValueInt64(), plan.RoleName.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceRoleScaleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state serviceRoleScaleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	info, err := controller.Service(int(state.ServiceID.ValueInt64())).Info()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read OneFlow service", err.Error())
+		return
+	}
+
+	for _, role := range info.Roles {
+		if role.Name == state.RoleName.ValueString() {
+			state.Cardinality = types.Int64Value(int64(role.Cardinality))
+			break
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *serviceRoleScaleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan serviceRoleScaleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.scale(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Failed to scale OneFlow service role", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceRoleScaleResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// Scaling is not reversible in any well-defined way (there is no
+	// "original" cardinality to return to), so destroying this resource
+	// only forgets it; the role is left at its last requested
+	// cardinality.
+}
+
+// scale issues the scale request for m and, if set, waits out its
+// cooldown before returning.
+func (r *serviceRoleScaleResource) scale(ctx context.Context, m serviceRoleScaleModel) error {
+	controller, err := controllerFor(r.cfg, m.Context.ValueString())
+	if err != nil {
+		return err
+	}
+
+	role := controller.Service(int(m.ServiceID.ValueInt64())).Role(m.RoleName.ValueString())
+	if err := role.Scale(int(m.Cardinality.ValueInt64()), m.Force.ValueBool()); err != nil {
+		return err
+	}
+
+	if !m.Cooldown.IsNull() && m.Cooldown.ValueString() != "" {
+		cooldown, err := time.ParseDuration(m.Cooldown.ValueString())
+		if err != nil {
+			return fmt.Errorf("cooldown: %w", err)
+		}
+		select {
+		case <-time.After(cooldown):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,361 @@
+package flow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/config"
+)
+
+// serviceTemplateModel is the `opennebula_service_template` resource model.
+type serviceTemplateModel struct {
+	ID       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Template types.String `tfsdk:"template"`
+	Tags     types.Map    `tfsdk:"tags"`
+	TagsAll  types.Map    `tfsdk:"tags_all"`
+	Context  types.String `tfsdk:"context"`
+}
+
+type serviceTemplateResource struct {
+	cfg *config.Provider
+}
+
+// NewServiceTemplate returns the `opennebula_service_template` resource.
+func NewServiceTemplate() resource.Resource {
+	return &serviceTemplateResource{}
+}
+
+func (r *serviceTemplateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_template"
+}
+
+func (r *serviceTemplateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an OpenNebula OneFlow service template, describing a multi-tier application made up of one or more roles",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the service template",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the service template",
+			},
+			"template": schema.StringAttribute{
+				Required:    true,
+				Description: "OneFlow service template document as JSON (roles, networks, scaling policies), typically built with jsonencode()",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				Description: "Tags tracked in Terraform state for this service template",
+				ElementType: types.StringType,
+			},
+			"tags_all": schema.MapAttribute{
+				Computed:    true,
+				Description: "tags merged with the provider's default_tags, minus any ignore_tags matches. Diff suppression should compare against this instead of tags",
+				ElementType: types.StringType,
+			},
+			"context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this resource. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (r *serviceTemplateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected resource configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *serviceTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan serviceTemplateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	body, err := serviceTemplateBody(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template", err.Error())
+		return
+	}
+
+	id, err := controller.STemplates().Create(body)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create OneFlow service template", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(strconv.Itoa(id))
+	if err := r.cfg.ApplyTagsAll(ctx, plan.Context.ValueString(), &plan.Tags, &plan.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state serviceTemplateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template ID", err.Error())
+		return
+	}
+
+	info, err := controller.STemplate(id).Info()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read OneFlow service template", err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(info.Name)
+	templateJSON, err := json.Marshal(info.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode OneFlow service template body", err.Error())
+		return
+	}
+	state.Template = types.StringValue(string(templateJSON))
+	if err := r.cfg.ApplyTagsAll(ctx, state.Context.ValueString(), &state.Tags, &state.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *serviceTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan serviceTemplateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, plan.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template ID", err.Error())
+		return
+	}
+
+	body, err := serviceTemplateBody(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template", err.Error())
+		return
+	}
+
+	if err := controller.STemplate(id).Update(body, false); err != nil {
+		resp.Diagnostics.AddError("Failed to update OneFlow service template", err.Error())
+		return
+	}
+
+	if err := r.cfg.ApplyTagsAll(ctx, plan.Context.ValueString(), &plan.Tags, &plan.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *serviceTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state serviceTemplateModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(r.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template ID", err.Error())
+		return
+	}
+
+	if err := controller.STemplate(id).Delete(); err != nil {
+		resp.Diagnostics.AddError("Failed to delete OneFlow service template", err.Error())
+		return
+	}
+}
+
+func (r *serviceTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// serviceTemplateBody merges the user-supplied JSON document with the
+// resource's `name` attribute, so users don't have to repeat the name
+// inside the template JSON.
+func serviceTemplateBody(m serviceTemplateModel) (string, error) {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(m.Template.ValueString()), &body); err != nil {
+		return "", fmt.Errorf("template is not valid JSON: %w", err)
+	}
+	body["name"] = m.Name.ValueString()
+
+	out, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// serviceTemplateDataSource is the `opennebula_service_template` data
+// source: looks up an existing service template by ID.
+type serviceTemplateDataSource struct {
+	cfg *config.Provider
+}
+
+// NewServiceTemplateDataSource returns the `opennebula_service_template`
+// data source.
+func NewServiceTemplateDataSource() datasource.DataSource {
+	return &serviceTemplateDataSource{}
+}
+
+func (d *serviceTemplateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_template"
+}
+
+func (d *serviceTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = dschema.Schema{
+		Description: "Retrieves information about an existing OneFlow service template",
+		Attributes: map[string]dschema.Attribute{
+			"id": dschema.StringAttribute{
+				Required:    true,
+				Description: "ID of the service template",
+			},
+			"name": dschema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the service template",
+			},
+			"template": dschema.StringAttribute{
+				Computed:    true,
+				Description: "OneFlow service template document as JSON",
+			},
+			"tags": dschema.MapAttribute{
+				Computed:    true,
+				Description: "Always null: OneFlow does not store Terraform-managed tags for existing service templates",
+				ElementType: types.StringType,
+			},
+			"tags_all": dschema.MapAttribute{
+				Computed:    true,
+				Description: "tags merged with the provider's default_tags, minus any ignore_tags matches",
+				ElementType: types.StringType,
+			},
+			"context": dschema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the provider profile to use for this data source. Defaults to the provider's current_context",
+			},
+		},
+	}
+}
+
+func (d *serviceTemplateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	cfg, ok := req.ProviderData.(*config.Provider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected data source configure type",
+			fmt.Sprintf("Expected *config.Provider, got %T", req.ProviderData),
+		)
+		return
+	}
+	d.cfg = cfg
+}
+
+func (d *serviceTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state serviceTemplateModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	controller, err := controllerFor(d.cfg, state.Context.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to resolve OpenNebula context", err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid service template ID", err.Error())
+		return
+	}
+
+	info, err := controller.STemplate(id).Info()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read OneFlow service template", err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(info.Name)
+	templateJSON, err := json.Marshal(info.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode OneFlow service template body", err.Error())
+		return
+	}
+	state.Template = types.StringValue(string(templateJSON))
+
+	// OneFlow does not track Terraform-managed tags for existing service
+	// templates, so the data source only surfaces the provider's
+	// default_tags via tags_all.
+	state.Tags = types.MapNull(types.StringType)
+	if err := d.cfg.ApplyTagsAll(ctx, state.Context.ValueString(), &state.Tags, &state.TagsAll); err != nil {
+		resp.Diagnostics.AddError("Failed to compute tags_all", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
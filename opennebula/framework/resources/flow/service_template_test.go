@@ -0,0 +1,62 @@
+package flow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestServiceTemplateBodySetsName(t *testing.T) {
+	m := serviceTemplateModel{
+		Name:     types.StringValue("my-service"),
+		Template: types.StringValue(`{"roles": []}`),
+	}
+
+	out, err := serviceTemplateBody(m)
+	if err != nil {
+		t.Fatalf("serviceTemplateBody: unexpected error: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &body); err != nil {
+		t.Fatalf("serviceTemplateBody produced invalid JSON: %v", err)
+	}
+	if body["name"] != "my-service" {
+		t.Errorf("body[\"name\"] = %v, want %q", body["name"], "my-service")
+	}
+	if _, ok := body["roles"]; !ok {
+		t.Errorf("body missing roles from the original template: %v", body)
+	}
+}
+
+func TestServiceTemplateBodyOverridesExistingName(t *testing.T) {
+	m := serviceTemplateModel{
+		Name:     types.StringValue("canonical-name"),
+		Template: types.StringValue(`{"name": "stale-name"}`),
+	}
+
+	out, err := serviceTemplateBody(m)
+	if err != nil {
+		t.Fatalf("serviceTemplateBody: unexpected error: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &body); err != nil {
+		t.Fatalf("serviceTemplateBody produced invalid JSON: %v", err)
+	}
+	if body["name"] != "canonical-name" {
+		t.Errorf("body[\"name\"] = %v, want the resource's name attribute to win", body["name"])
+	}
+}
+
+func TestServiceTemplateBodyInvalidJSON(t *testing.T) {
+	m := serviceTemplateModel{
+		Name:     types.StringValue("my-service"),
+		Template: types.StringValue(`not json`),
+	}
+
+	if _, err := serviceTemplateBody(m); err == nil {
+		t.Fatal("serviceTemplateBody: expected an error for invalid template JSON")
+	}
+}
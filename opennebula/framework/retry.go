@@ -0,0 +1,69 @@
+package opennebula
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/OpenNebula/terraform-provider-opennebula/opennebula/framework/transport"
+)
+
+// resolveRetryConfig parses the `retry` and `rate_limit` provider blocks
+// into the transport package's configuration types.
+func resolveRetryConfig(ctx context.Context, m opennebulaProviderModel) (transport.RetryConfig, transport.RateLimitConfig, error) {
+	retryCfg := transport.RetryConfig{MaxAttempts: 1}
+	rateCfg := transport.RateLimitConfig{}
+
+	if !m.Retry.IsNull() {
+		var r retryModel
+		if diags := m.Retry.As(ctx, &r, basetypes.ObjectAsOptions{}); diags.HasError() {
+			return retryCfg, rateCfg, fmt.Errorf("failed to read retry block: %v", diags.Errors())
+		}
+
+		if !r.MaxAttempts.IsNull() {
+			retryCfg.MaxAttempts = int(r.MaxAttempts.ValueInt64())
+		}
+
+		if !r.MinBackoff.IsNull() {
+			d, err := time.ParseDuration(r.MinBackoff.ValueString())
+			if err != nil {
+				return retryCfg, rateCfg, fmt.Errorf("retry.min_backoff: %w", err)
+			}
+			retryCfg.MinBackoff = d
+		}
+
+		if !r.MaxBackoff.IsNull() {
+			d, err := time.ParseDuration(r.MaxBackoff.ValueString())
+			if err != nil {
+				return retryCfg, rateCfg, fmt.Errorf("retry.max_backoff: %w", err)
+			}
+			retryCfg.MaxBackoff = d
+		}
+
+		if !r.RetryOn.IsNull() {
+			var kinds []string
+			if diags := r.RetryOn.ElementsAs(ctx, &kinds, false); diags.HasError() {
+				return retryCfg, rateCfg, fmt.Errorf("failed to read retry.retry_on: %v", diags.Errors())
+			}
+			retryCfg.RetryOn = kinds
+		}
+	}
+
+	if !m.RateLimit.IsNull() {
+		var rl rateLimitModel
+		if diags := m.RateLimit.As(ctx, &rl, basetypes.ObjectAsOptions{}); diags.HasError() {
+			return retryCfg, rateCfg, fmt.Errorf("failed to read rate_limit block: %v", diags.Errors())
+		}
+
+		if !rl.RPS.IsNull() {
+			rateCfg.RPS = rl.RPS.ValueFloat64()
+		}
+		if !rl.Burst.IsNull() {
+			rateCfg.Burst = int(rl.Burst.ValueInt64())
+		}
+	}
+
+	return retryCfg, rateCfg, nil
+}
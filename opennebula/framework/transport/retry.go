@@ -0,0 +1,226 @@
+// Package transport provides an http.RoundTripper that adds retry/backoff
+// and client-side rate limiting around calls to the OpenNebula XML-RPC and
+// Flow endpoints, so individual resources don't have to hand-roll timeouts
+// against flaky ONE frontends.
+package transport
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// RetryConfig controls the retry/backoff behavior of RetryingTransport.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts per request,
+	// including the first. A value <= 1 disables retries.
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// RetryOn lists the failure classes to retry: "timeout", "5xx",
+	// "rpc_busy".
+	RetryOn []string
+}
+
+// RateLimitConfig throttles outbound requests through a token bucket.
+type RateLimitConfig struct {
+	// RPS is the sustained request rate. A value <= 0 disables
+	// throttling.
+	RPS   float64
+	Burst int
+}
+
+// RetryingTransport wraps an http.RoundTripper with exponential backoff
+// (full jitter) retries and an optional rate limiter. It classifies
+// failures into retryable/non-retryable before deciding whether to retry:
+// network errors, HTTP 5xx responses, and OpenNebula XML-RPC faults
+// reporting that the frontend is busy.
+type RetryingTransport struct {
+	Base    http.RoundTripper
+	Retry   RetryConfig
+	Limiter *rate.Limiter
+
+	attempts  int64
+	retries   int64
+	throttled int64
+}
+
+// NewRetryingTransport builds a RetryingTransport around base. A nil base
+// defaults to http.DefaultTransport.
+func NewRetryingTransport(base http.RoundTripper, retry RetryConfig, rl RateLimitConfig) *RetryingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 1
+	}
+	if retry.MaxAttempts > 1 && len(retry.RetryOn) == 0 {
+		retry.RetryOn = []string{"timeout", "5xx", "rpc_busy"}
+	}
+
+	var limiter *rate.Limiter
+	if rl.RPS > 0 {
+		burst := rl.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(rl.RPS), burst)
+	}
+
+	return &RetryingTransport{Base: base, Retry: retry, Limiter: limiter}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < t.Retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			wait := t.backoff(attempt)
+			tflog.Debug(ctx, "opennebula: retrying request", map[string]interface{}{
+				"attempt": attempt + 1,
+				"backoff": wait.String(),
+			})
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if t.Limiter != nil {
+			waitStart := time.Now()
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+			if time.Since(waitStart) > time.Millisecond {
+				atomic.AddInt64(&t.throttled, 1)
+			}
+		}
+
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		atomic.AddInt64(&t.attempts, 1)
+		resp, err := t.Base.RoundTrip(req)
+		if !t.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		lastErr = err
+		if resp != nil {
+			resp.Body.Close()
+		}
+		atomic.AddInt64(&t.retries, 1)
+	}
+
+	tflog.Debug(ctx, "opennebula: giving up retrying request", map[string]interface{}{
+		"attempts":  atomic.LoadInt64(&t.attempts),
+		"retries":   atomic.LoadInt64(&t.retries),
+		"throttled": atomic.LoadInt64(&t.throttled),
+	})
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("giving up after %d attempts: %w", t.Retry.MaxAttempts, lastErr)
+	}
+	return nil, fmt.Errorf("giving up after %d attempts", t.Retry.MaxAttempts)
+}
+
+// Counters reports the number of attempts, retries, and rate-limit waits
+// observed so far, for callers that want to tflog them alongside their own
+// context.
+func (t *RetryingTransport) Counters() (attempts, retries, throttled int64) {
+	return atomic.LoadInt64(&t.attempts), atomic.LoadInt64(&t.retries), atomic.LoadInt64(&t.throttled)
+}
+
+func (t *RetryingTransport) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return t.retryOn("timeout") && isTimeout(err)
+	}
+	if resp.StatusCode >= 500 && t.retryOn("5xx") {
+		return true
+	}
+	if t.retryOn("rpc_busy") && isRPCBusy(resp) {
+		return true
+	}
+	return false
+}
+
+func (t *RetryingTransport) retryOn(kind string) bool {
+	for _, k := range t.Retry.RetryOn {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isRPCBusy sniffs the response body for an OpenNebula XML-RPC fault
+// indicating the frontend is busy (e.g. a locked object), restoring the
+// body afterwards so the caller can still read it.
+func isRPCBusy(resp *http.Response) bool {
+	if resp == nil || resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(body), "ACTION is already in progress") ||
+		strings.Contains(string(body), "Cannot perform action")
+}
+
+// backoff returns an exponential delay with full jitter, bounded by
+// MinBackoff/MaxBackoff.
+func (t *RetryingTransport) backoff(attempt int) time.Duration {
+	min := t.Retry.MinBackoff
+	if min <= 0 {
+		min = 500 * time.Millisecond
+	}
+	max := t.Retry.MaxBackoff
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+
+	capped := time.Duration(math.Min(float64(max), float64(min)*math.Pow(2, float64(attempt))))
+	if capped <= min {
+		return min
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(capped-min)))
+	if err != nil {
+		return min
+	}
+	return min + time.Duration(n.Int64())
+}
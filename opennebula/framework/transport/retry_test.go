@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewRetryingTransportDefaultsRetryOn(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{MaxAttempts: 5}, RateLimitConfig{})
+
+	for _, kind := range []string{"timeout", "5xx", "rpc_busy"} {
+		if !tr.retryOn(kind) {
+			t.Errorf("expected retry_on to default to include %q when unset", kind)
+		}
+	}
+}
+
+func TestNewRetryingTransportRespectsExplicitRetryOn(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{MaxAttempts: 5, RetryOn: []string{"5xx"}}, RateLimitConfig{})
+
+	if !tr.retryOn("5xx") {
+		t.Error("expected 5xx to be retryable")
+	}
+	if tr.retryOn("timeout") {
+		t.Error("expected timeout to not be retryable when retry_on only lists 5xx")
+	}
+}
+
+func TestNewRetryingTransportNoRetriesLeavesRetryOnEmpty(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{}, RateLimitConfig{})
+
+	if tr.retryOn("5xx") {
+		t.Error("expected no retry classes when max_attempts defaults to 1")
+	}
+}
+
+func TestShouldRetryOn5xx(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{MaxAttempts: 3, RetryOn: []string{"5xx"}}, RateLimitConfig{})
+
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	if !tr.shouldRetry(resp, nil) {
+		t.Error("expected a 503 response to be retried when 5xx is enabled")
+	}
+
+	resp2 := &http.Response{StatusCode: http.StatusOK}
+	if tr.shouldRetry(resp2, nil) {
+		t.Error("expected a 200 response to never be retried")
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{
+		MaxAttempts: 10,
+		MinBackoff:  100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}, RateLimitConfig{})
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := tr.backoff(attempt)
+		if d < tr.Retry.MinBackoff {
+			t.Errorf("attempt %d: backoff %s below min %s", attempt, d, tr.Retry.MinBackoff)
+		}
+		if d > tr.Retry.MaxBackoff {
+			t.Errorf("attempt %d: backoff %s above max %s", attempt, d, tr.Retry.MaxBackoff)
+		}
+	}
+}
+
+func TestBackoffDefaultsWhenUnset(t *testing.T) {
+	tr := NewRetryingTransport(nil, RetryConfig{MaxAttempts: 2}, RateLimitConfig{})
+
+	d := tr.backoff(1)
+	if d < 500*time.Millisecond || d > 30*time.Second {
+		t.Errorf("expected backoff within default 500ms-30s bounds, got %s", d)
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"log"
+	"sync"
+)
+
+// MutexKV is a simple key/value store for arbitrary mutexes. It can be used
+// to serialize access to resources that must not be operated on concurrently,
+// such as OpenNebula objects that do not support concurrent updates on the
+// API side (e.g. VM NIC attachment, image pool state changes).
+type MutexKV struct {
+	lock  sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+// Lock locks the mutex for the given key, creating it if it does not exist.
+func (m *MutexKV) Lock(key string) {
+	log.Printf("[DEBUG] Locking %q", key)
+	m.get(key).Lock()
+	log.Printf("[DEBUG] Locked %q", key)
+}
+
+// Unlock unlocks the mutex for the given key. It is a no-op if the key is
+// not known.
+func (m *MutexKV) Unlock(key string) {
+	m.lock.Lock()
+	mutex, ok := m.store[key]
+	m.lock.Unlock()
+	if !ok {
+		return
+	}
+	log.Printf("[DEBUG] Unlocking %q", key)
+	mutex.Unlock()
+	log.Printf("[DEBUG] Unlocked %q", key)
+}
+
+// get returns a mutex for the given key, creating it if it doesn't already
+// exist.
+func (m *MutexKV) get(key string) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
+
+// NewMutexKV returns a properly initialized MutexKV.
+func NewMutexKV() *MutexKV {
+	return &MutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
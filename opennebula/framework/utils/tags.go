@@ -0,0 +1,57 @@
+package utils
+
+import "strings"
+
+// IgnoreTags describes provider-level tag keys/prefixes that are never
+// considered part of `tags_all`, so tags injected by external tooling
+// (e.g. a CI pipeline) on existing OpenNebula objects don't show up as
+// drift.
+type IgnoreTags struct {
+	KeyPrefixes []string
+	Keys        []string
+}
+
+// IsIgnored reports whether key matches one of the configured ignore
+// rules.
+func (i IgnoreTags) IsIgnored(key string) bool {
+	for _, k := range i.Keys {
+		if k == key {
+			return true
+		}
+	}
+	for _, prefix := range i.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeTags merges the given tag maps in order, with later maps
+// overriding earlier ones on key conflicts. This implements the
+// declaration-order merge used for repeated `default_tags` blocks.
+func MergeTags(maps ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// TagsAll computes the `tags_all` attribute every framework resource
+// exposes: the resource's own `tags` overlaid on the provider's
+// default_tags, with any ignored keys stripped. Plan-time diff
+// suppression should compare against this, not against the raw `tags`
+// attribute, so a default tag appearing/disappearing doesn't force a
+// resource update.
+func TagsAll(resourceTags, defaultTags map[string]string, ignore IgnoreTags) map[string]string {
+	merged := MergeTags(defaultTags, resourceTags)
+	for k := range merged {
+		if ignore.IsIgnored(k) {
+			delete(merged, k)
+		}
+	}
+	return merged
+}
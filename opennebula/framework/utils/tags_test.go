@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIgnoreTagsIsIgnored(t *testing.T) {
+	ignore := IgnoreTags{
+		Keys:        []string{"Owner"},
+		KeyPrefixes: []string{"aws:"},
+	}
+
+	cases := map[string]bool{
+		"Owner":                    true,
+		"aws:cloudformation:stack": true,
+		"Name":                     false,
+		"owner":                    false,
+	}
+	for key, want := range cases {
+		if got := ignore.IsIgnored(key); got != want {
+			t.Errorf("IsIgnored(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMergeTagsOrder(t *testing.T) {
+	got := MergeTags(
+		map[string]string{"env": "dev", "owner": "team-a"},
+		map[string]string{"env": "prod"},
+	)
+	want := map[string]string{"env": "prod", "owner": "team-a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags = %v, want %v", got, want)
+	}
+}
+
+func TestMergeTagsNoMaps(t *testing.T) {
+	got := MergeTags()
+	if len(got) != 0 {
+		t.Errorf("MergeTags() with no args = %v, want empty map", got)
+	}
+}
+
+func TestTagsAll(t *testing.T) {
+	resourceTags := map[string]string{"Name": "web", "aws:createdBy": "ci"}
+	defaultTags := map[string]string{"env": "prod", "Name": "default-name"}
+	ignore := IgnoreTags{KeyPrefixes: []string{"aws:"}}
+
+	got := TagsAll(resourceTags, defaultTags, ignore)
+	want := map[string]string{"env": "prod", "Name": "web"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TagsAll = %v, want %v", got, want)
+	}
+}
+
+func TestTagsAllNoIgnoreRules(t *testing.T) {
+	got := TagsAll(map[string]string{"a": "1"}, map[string]string{"b": "2"}, IgnoreTags{})
+	want := map[string]string{"a": "1", "b": "2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TagsAll = %v, want %v", got, want)
+	}
+}